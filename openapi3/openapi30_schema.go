@@ -0,0 +1,520 @@
+package openapi3
+
+// openapi30MetaSchema is the JSON Schema that validates the shape of an
+// OpenAPI 3.0 document itself, vendored (and trimmed of some of the
+// upstream schema's more exotic format/pattern constraints, to keep this
+// file a reasonable size). It's preloaded into NewSwaggerLoader's
+// resolution cache under openapi30MetaSchemaURL, so a spec whose external
+// $ref happens to target that URL (e.g.
+// "https://spec.openapis.org/oas/3.0/schema/2021-09-28#/definitions/Schema")
+// never pays a network round trip for it: loadExternalDocument serves it as
+// a raw JSON-Schema node rather than unmarshaling it into a *Swagger, which
+// shares none of this document's field names and would come back empty.
+const openapi30MetaSchema = `{
+  "$id": "https://spec.openapis.org/oas/3.0/schema/2021-09-28",
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "type": "object",
+  "required": ["openapi", "info", "paths"],
+  "properties": {
+    "openapi": {
+      "type": "string",
+      "pattern": "^3\\.0\\.\\d(-.+)?$"
+    },
+    "info": { "$ref": "#/definitions/Info" },
+    "externalDocs": { "$ref": "#/definitions/ExternalDocumentation" },
+    "servers": {
+      "type": "array",
+      "items": { "$ref": "#/definitions/Server" }
+    },
+    "security": {
+      "type": "array",
+      "items": { "$ref": "#/definitions/SecurityRequirement" }
+    },
+    "tags": {
+      "type": "array",
+      "items": { "$ref": "#/definitions/Tag" },
+      "uniqueItems": true
+    },
+    "paths": { "$ref": "#/definitions/Paths" },
+    "components": { "$ref": "#/definitions/Components" }
+  },
+  "definitions": {
+    "Info": {
+      "type": "object",
+      "required": ["title", "version"],
+      "properties": {
+        "title": { "type": "string" },
+        "description": { "type": "string" },
+        "termsOfService": { "type": "string" },
+        "contact": { "$ref": "#/definitions/Contact" },
+        "license": { "$ref": "#/definitions/License" },
+        "version": { "type": "string" }
+      }
+    },
+    "Contact": {
+      "type": "object",
+      "properties": {
+        "name": { "type": "string" },
+        "url": { "type": "string" },
+        "email": { "type": "string" }
+      }
+    },
+    "License": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": { "type": "string" },
+        "url": { "type": "string" }
+      }
+    },
+    "Server": {
+      "type": "object",
+      "required": ["url"],
+      "properties": {
+        "url": { "type": "string" },
+        "description": { "type": "string" },
+        "variables": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ServerVariable" }
+        }
+      }
+    },
+    "ServerVariable": {
+      "type": "object",
+      "required": ["default"],
+      "properties": {
+        "enum": { "type": "array", "items": { "type": "string" } },
+        "default": { "type": "string" },
+        "description": { "type": "string" }
+      }
+    },
+    "Components": {
+      "type": "object",
+      "properties": {
+        "schemas": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/SchemaOrReference" }
+        },
+        "responses": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ResponseOrReference" }
+        },
+        "parameters": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ParameterOrReference" }
+        },
+        "examples": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ExampleOrReference" }
+        },
+        "requestBodies": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/RequestBodyOrReference" }
+        },
+        "headers": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/HeaderOrReference" }
+        },
+        "securitySchemes": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/SecuritySchemeOrReference" }
+        },
+        "links": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/LinkOrReference" }
+        },
+        "callbacks": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/CallbackOrReference" }
+        }
+      }
+    },
+    "Paths": {
+      "type": "object",
+      "patternProperties": {
+        "^/": { "$ref": "#/definitions/PathItem" }
+      }
+    },
+    "PathItem": {
+      "type": "object",
+      "properties": {
+        "$ref": { "type": "string" },
+        "summary": { "type": "string" },
+        "description": { "type": "string" },
+        "get": { "$ref": "#/definitions/Operation" },
+        "put": { "$ref": "#/definitions/Operation" },
+        "post": { "$ref": "#/definitions/Operation" },
+        "delete": { "$ref": "#/definitions/Operation" },
+        "options": { "$ref": "#/definitions/Operation" },
+        "head": { "$ref": "#/definitions/Operation" },
+        "patch": { "$ref": "#/definitions/Operation" },
+        "trace": { "$ref": "#/definitions/Operation" },
+        "servers": {
+          "type": "array",
+          "items": { "$ref": "#/definitions/Server" }
+        },
+        "parameters": {
+          "type": "array",
+          "items": { "$ref": "#/definitions/ParameterOrReference" }
+        }
+      }
+    },
+    "Operation": {
+      "type": "object",
+      "required": ["responses"],
+      "properties": {
+        "tags": { "type": "array", "items": { "type": "string" } },
+        "summary": { "type": "string" },
+        "description": { "type": "string" },
+        "externalDocs": { "$ref": "#/definitions/ExternalDocumentation" },
+        "operationId": { "type": "string" },
+        "parameters": {
+          "type": "array",
+          "items": { "$ref": "#/definitions/ParameterOrReference" }
+        },
+        "requestBody": { "$ref": "#/definitions/RequestBodyOrReference" },
+        "responses": { "$ref": "#/definitions/Responses" },
+        "callbacks": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/CallbackOrReference" }
+        },
+        "deprecated": { "type": "boolean" },
+        "security": {
+          "type": "array",
+          "items": { "$ref": "#/definitions/SecurityRequirement" }
+        },
+        "servers": {
+          "type": "array",
+          "items": { "$ref": "#/definitions/Server" }
+        }
+      }
+    },
+    "ExternalDocumentation": {
+      "type": "object",
+      "required": ["url"],
+      "properties": {
+        "description": { "type": "string" },
+        "url": { "type": "string" }
+      }
+    },
+    "Parameter": {
+      "type": "object",
+      "required": ["name", "in"],
+      "properties": {
+        "name": { "type": "string" },
+        "in": { "type": "string", "enum": ["query", "header", "path", "cookie"] },
+        "description": { "type": "string" },
+        "required": { "type": "boolean" },
+        "deprecated": { "type": "boolean" },
+        "allowEmptyValue": { "type": "boolean" },
+        "style": { "type": "string" },
+        "explode": { "type": "boolean" },
+        "allowReserved": { "type": "boolean" },
+        "schema": { "$ref": "#/definitions/SchemaOrReference" },
+        "example": {},
+        "examples": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ExampleOrReference" }
+        },
+        "content": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/MediaType" }
+        }
+      }
+    },
+    "ParameterOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Parameter" }
+      ]
+    },
+    "RequestBody": {
+      "type": "object",
+      "required": ["content"],
+      "properties": {
+        "description": { "type": "string" },
+        "content": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/MediaType" }
+        },
+        "required": { "type": "boolean" }
+      }
+    },
+    "RequestBodyOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/RequestBody" }
+      ]
+    },
+    "MediaType": {
+      "type": "object",
+      "properties": {
+        "schema": { "$ref": "#/definitions/SchemaOrReference" },
+        "example": {},
+        "examples": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ExampleOrReference" }
+        },
+        "encoding": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/Encoding" }
+        }
+      }
+    },
+    "Encoding": {
+      "type": "object",
+      "properties": {
+        "contentType": { "type": "string" },
+        "headers": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/HeaderOrReference" }
+        },
+        "style": { "type": "string" },
+        "explode": { "type": "boolean" },
+        "allowReserved": { "type": "boolean" }
+      }
+    },
+    "Responses": {
+      "type": "object",
+      "properties": {
+        "default": { "$ref": "#/definitions/ResponseOrReference" }
+      },
+      "patternProperties": {
+        "^[1-5](?:[0-9]{2}|XX)$": { "$ref": "#/definitions/ResponseOrReference" }
+      },
+      "minProperties": 1
+    },
+    "Response": {
+      "type": "object",
+      "required": ["description"],
+      "properties": {
+        "description": { "type": "string" },
+        "headers": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/HeaderOrReference" }
+        },
+        "content": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/MediaType" }
+        },
+        "links": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/LinkOrReference" }
+        }
+      }
+    },
+    "ResponseOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Response" }
+      ]
+    },
+    "Callback": {
+      "type": "object",
+      "additionalProperties": { "$ref": "#/definitions/PathItem" }
+    },
+    "CallbackOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Callback" }
+      ]
+    },
+    "Example": {
+      "type": "object",
+      "properties": {
+        "summary": { "type": "string" },
+        "description": { "type": "string" },
+        "value": {},
+        "externalValue": { "type": "string" }
+      }
+    },
+    "ExampleOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Example" }
+      ]
+    },
+    "Link": {
+      "type": "object",
+      "properties": {
+        "operationRef": { "type": "string" },
+        "operationId": { "type": "string" },
+        "parameters": { "type": "object" },
+        "requestBody": {},
+        "description": { "type": "string" },
+        "server": { "$ref": "#/definitions/Server" }
+      }
+    },
+    "LinkOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Link" }
+      ]
+    },
+    "Header": {
+      "type": "object",
+      "properties": {
+        "description": { "type": "string" },
+        "required": { "type": "boolean" },
+        "deprecated": { "type": "boolean" },
+        "allowEmptyValue": { "type": "boolean" },
+        "style": { "type": "string" },
+        "explode": { "type": "boolean" },
+        "allowReserved": { "type": "boolean" },
+        "schema": { "$ref": "#/definitions/SchemaOrReference" },
+        "example": {},
+        "examples": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/ExampleOrReference" }
+        },
+        "content": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/MediaType" }
+        }
+      }
+    },
+    "HeaderOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Header" }
+      ]
+    },
+    "Tag": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": { "type": "string" },
+        "description": { "type": "string" },
+        "externalDocs": { "$ref": "#/definitions/ExternalDocumentation" }
+      }
+    },
+    "Reference": {
+      "type": "object",
+      "required": ["$ref"],
+      "properties": {
+        "$ref": { "type": "string" }
+      }
+    },
+    "Schema": {
+      "type": "object",
+      "properties": {
+        "title": { "type": "string" },
+        "multipleOf": { "type": "number", "minimum": 0, "exclusiveMinimum": true },
+        "maximum": { "type": "number" },
+        "exclusiveMaximum": { "type": "boolean" },
+        "minimum": { "type": "number" },
+        "exclusiveMinimum": { "type": "boolean" },
+        "maxLength": { "type": "integer", "minimum": 0 },
+        "minLength": { "type": "integer", "minimum": 0 },
+        "pattern": { "type": "string" },
+        "maxItems": { "type": "integer", "minimum": 0 },
+        "minItems": { "type": "integer", "minimum": 0 },
+        "uniqueItems": { "type": "boolean" },
+        "maxProperties": { "type": "integer", "minimum": 0 },
+        "minProperties": { "type": "integer", "minimum": 0 },
+        "required": { "type": "array", "items": { "type": "string" }, "minItems": 1 },
+        "enum": { "type": "array", "minItems": 1 },
+        "type": {
+          "type": "string",
+          "enum": ["array", "boolean", "integer", "number", "object", "string"]
+        },
+        "allOf": { "type": "array", "items": { "$ref": "#/definitions/SchemaOrReference" } },
+        "oneOf": { "type": "array", "items": { "$ref": "#/definitions/SchemaOrReference" } },
+        "anyOf": { "type": "array", "items": { "$ref": "#/definitions/SchemaOrReference" } },
+        "not": { "$ref": "#/definitions/SchemaOrReference" },
+        "items": { "$ref": "#/definitions/SchemaOrReference" },
+        "properties": {
+          "type": "object",
+          "additionalProperties": { "$ref": "#/definitions/SchemaOrReference" }
+        },
+        "additionalProperties": {
+          "oneOf": [
+            { "type": "boolean" },
+            { "$ref": "#/definitions/SchemaOrReference" }
+          ]
+        },
+        "description": { "type": "string" },
+        "format": { "type": "string" },
+        "default": {},
+        "nullable": { "type": "boolean" },
+        "discriminator": { "$ref": "#/definitions/Discriminator" },
+        "readOnly": { "type": "boolean" },
+        "writeOnly": { "type": "boolean" },
+        "example": {},
+        "externalDocs": { "$ref": "#/definitions/ExternalDocumentation" },
+        "deprecated": { "type": "boolean" },
+        "xml": { "$ref": "#/definitions/XML" }
+      }
+    },
+    "SchemaOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/Schema" }
+      ]
+    },
+    "Discriminator": {
+      "type": "object",
+      "required": ["propertyName"],
+      "properties": {
+        "propertyName": { "type": "string" },
+        "mapping": { "type": "object", "additionalProperties": { "type": "string" } }
+      }
+    },
+    "XML": {
+      "type": "object",
+      "properties": {
+        "name": { "type": "string" },
+        "namespace": { "type": "string" },
+        "prefix": { "type": "string" },
+        "attribute": { "type": "boolean" },
+        "wrapped": { "type": "boolean" }
+      }
+    },
+    "SecurityScheme": {
+      "type": "object",
+      "required": ["type"],
+      "properties": {
+        "type": { "type": "string", "enum": ["apiKey", "http", "oauth2", "openIdConnect"] },
+        "description": { "type": "string" },
+        "name": { "type": "string" },
+        "in": { "type": "string", "enum": ["query", "header", "cookie"] },
+        "scheme": { "type": "string" },
+        "bearerFormat": { "type": "string" },
+        "flows": { "$ref": "#/definitions/OAuthFlows" },
+        "openIdConnectUrl": { "type": "string" }
+      }
+    },
+    "SecuritySchemeOrReference": {
+      "oneOf": [
+        { "$ref": "#/definitions/Reference" },
+        { "$ref": "#/definitions/SecurityScheme" }
+      ]
+    },
+    "OAuthFlows": {
+      "type": "object",
+      "properties": {
+        "implicit": { "$ref": "#/definitions/OAuthFlow" },
+        "password": { "$ref": "#/definitions/OAuthFlow" },
+        "clientCredentials": { "$ref": "#/definitions/OAuthFlow" },
+        "authorizationCode": { "$ref": "#/definitions/OAuthFlow" }
+      }
+    },
+    "OAuthFlow": {
+      "type": "object",
+      "required": ["scopes"],
+      "properties": {
+        "authorizationUrl": { "type": "string" },
+        "tokenUrl": { "type": "string" },
+        "refreshUrl": { "type": "string" },
+        "scopes": { "type": "object", "additionalProperties": { "type": "string" } }
+      }
+    },
+    "SecurityRequirement": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "array",
+        "items": { "type": "string" }
+      }
+    }
+  }
+}`
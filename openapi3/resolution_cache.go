@@ -0,0 +1,43 @@
+package openapi3
+
+import "sync"
+
+// memoryResolutionCache is the default, thread-safe in-memory
+// ResolutionCache used by NewSwaggerLoader.
+type memoryResolutionCache struct {
+	mu      sync.RWMutex
+	entries map[string]interface{}
+}
+
+// NewMemoryResolutionCache returns an empty, thread-safe in-memory
+// ResolutionCache suitable for assigning to SwaggerLoader.Cache.
+func NewMemoryResolutionCache() ResolutionCache {
+	return &memoryResolutionCache{entries: make(map[string]interface{})}
+}
+
+func (cache *memoryResolutionCache) Get(key string) (interface{}, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	value, ok := cache.entries[key]
+	return value, ok
+}
+
+func (cache *memoryResolutionCache) Set(key string, value interface{}) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[key] = value
+}
+
+// openapi30MetaSchemaURL is the canonical, stable location of the OpenAPI
+// 3.0 meta-schema. Specs routinely $ref it for tooling-level validation, so
+// NewSwaggerLoader preloads it into the resolution cache rather than paying
+// a network round trip for it on every load.
+const openapi30MetaSchemaURL = "https://spec.openapis.org/oas/3.0/schema/2021-09-28"
+
+// newPreloadedResolutionCache returns a memoryResolutionCache with the
+// OpenAPI 3.0 meta-schema already populated under its canonical URL.
+func newPreloadedResolutionCache() ResolutionCache {
+	cache := NewMemoryResolutionCache()
+	cache.Set(openapi30MetaSchemaURL, []byte(openapi30MetaSchema))
+	return cache
+}
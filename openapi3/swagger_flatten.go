@@ -0,0 +1,352 @@
+package openapi3
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// errCircularRef is an internal sentinel: it tells the ref-wrapper frame
+// that closes a reference cycle to leave its own $ref exactly as it already
+// was, instead of clearing it, rather than bubbling up as a real error.
+var errCircularRef = errors.New("openapi3: circular reference, left unexpanded")
+
+// ExpandOptions controls how (*SwaggerLoader).Expand inlines $refs.
+type ExpandOptions struct {
+	// SkipSchemas leaves every schema ref (a SchemaRef, whatever it points
+	// at) untouched, inlining only the other ref kinds: parameters,
+	// responses, request bodies, headers, examples, links, callbacks and
+	// security schemes.
+	SkipSchemas bool
+	// ContinueOnError keeps expanding the rest of the document instead of
+	// aborting the first time a ref fails to resolve.
+	ContinueOnError bool
+	// AbsoluteCircularRef controls how a reference cycle is broken: when
+	// true, the edge that would close the cycle is left exactly as it
+	// already was - a $ref, not inlined - instead of being followed forever;
+	// when false, the cycle is an error: Expand returns it as soon as it
+	// detects the cycle, unless ContinueOnError is set, in which case that
+	// edge is left as it was and expansion goes on.
+	AbsoluteCircularRef bool
+}
+
+// componentRefKind describes one of the ref-and-value pairs Flatten/Expand
+// know how to hoist into, or inline out of, #/components/<name>/...
+type componentRefKind struct {
+	name      string       // plural key under Components, e.g. "schemas"
+	fieldName string       // exported Components field, e.g. "Schemas"
+	refType   reflect.Type // e.g. reflect.TypeOf(SchemaRef{})
+}
+
+func componentRefKinds() []componentRefKind {
+	return []componentRefKind{
+		{"schemas", "Schemas", reflect.TypeOf(SchemaRef{})},
+		{"parameters", "Parameters", reflect.TypeOf(ParameterRef{})},
+		{"responses", "Responses", reflect.TypeOf(ResponseRef{})},
+		{"requestBodies", "RequestBodies", reflect.TypeOf(RequestBodyRef{})},
+		{"headers", "Headers", reflect.TypeOf(HeaderRef{})},
+		{"examples", "Examples", reflect.TypeOf(ExampleRef{})},
+	}
+}
+
+func componentRefKindOf(t reflect.Type) (componentRefKind, bool) {
+	for _, kind := range componentRefKinds() {
+		if kind.refType == t {
+			return kind, true
+		}
+	}
+	return componentRefKind{}, false
+}
+
+// refWrapperFields reports whether v is one of the `*XRef` wrapper structs
+// (a string `Ref` field alongside a pointer `Value` field) and, if so,
+// returns those two fields.
+func refWrapperFields(v reflect.Value) (refField, valueField reflect.Value, ok bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+	refField = v.FieldByName("Ref")
+	valueField = v.FieldByName("Value")
+	if !refField.IsValid() || refField.Kind() != reflect.String || !valueField.IsValid() {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+	return refField, valueField, true
+}
+
+// walkRefs recursively visits every reachable node in v, calling visit once
+// for every `*XRef` wrapper it finds (in depth-first, parent-before-child
+// order), skipping back into nodes already on the current path to guard
+// against cycles.
+func walkRefs(v reflect.Value, inProgress map[interface{}]struct{}, visit func(structPtr reflect.Value, refField, valueField reflect.Value) error) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if _, seen := inProgress[v.Interface()]; seen {
+				return nil
+			}
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if refField, valueField, ok := refWrapperFields(v); ok {
+			if err := visit(v.Addr(), refField, valueField); err != nil {
+				return err
+			}
+			ptr := v.Addr().Interface()
+			inProgress[ptr] = struct{}{}
+			err := walkRefs(valueField, inProgress, visit)
+			delete(inProgress, ptr)
+			return err
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanInterface() {
+				continue
+			}
+			if err := walkRefs(v.Field(i), inProgress, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if err := walkRefs(v.MapIndex(key), inProgress, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkRefs(v.Index(i), inProgress, visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flattener hoists external or inline refs into swagger.Components,
+// deduplicating by structural equality so two identical inline schemas
+// mint only one component.
+type flattener struct {
+	swagger *Swagger
+	byHash  map[string]map[string]string // kind name -> structural hash -> minted name
+	// roots holds the *XRef pointers that are themselves the value stored
+	// directly under a Components.<kind> map, e.g. Components.Schemas["Pet"].
+	// Those are definitions, not references to one, so their Ref is
+	// legitimately "" - they must never be treated as inline values to hoist.
+	roots map[interface{}]struct{}
+}
+
+// Flatten walks every *Ref in swagger and rewrites any ref that is external,
+// or inline (i.e. a value with no Ref at all), into a newly minted entry
+// under #/components/{schemas,parameters,responses,requestBodies,headers,examples}/...,
+// so the resulting document is self-contained with only local refs.
+func (swaggerLoader *SwaggerLoader) Flatten(swagger *Swagger) error {
+	f := &flattener{
+		swagger: swagger,
+		byHash:  make(map[string]map[string]string),
+		roots:   componentRootPointers(swagger),
+	}
+	// Flatten is run using its own per-node visitation set: the shared
+	// swaggerLoader.visited map is built for resolution and may already
+	// contain every node, which would short-circuit this pass entirely.
+	inProgress := make(map[interface{}]struct{})
+	return walkRefs(reflect.ValueOf(swagger), inProgress, f.visit)
+}
+
+// componentRootPointers collects the *XRef pointers held directly as
+// Components.<kind> map values, for every kind Flatten hoists into.
+func componentRootPointers(swagger *Swagger) map[interface{}]struct{} {
+	roots := make(map[interface{}]struct{})
+	components := reflect.ValueOf(&swagger.Components).Elem()
+	for _, kind := range componentRefKinds() {
+		m := components.FieldByName(kind.fieldName)
+		if !m.IsValid() || m.IsNil() {
+			continue
+		}
+		for _, key := range m.MapKeys() {
+			if value := m.MapIndex(key); !value.IsNil() {
+				roots[value.Interface()] = struct{}{}
+			}
+		}
+	}
+	return roots
+}
+
+func (f *flattener) visit(structPtr reflect.Value, refField, valueField reflect.Value) error {
+	kind, ok := componentRefKindOf(structPtr.Elem().Type())
+	if !ok || valueField.IsNil() {
+		return nil
+	}
+	if _, isRoot := f.roots[structPtr.Interface()]; isRoot {
+		return nil
+	}
+	ref := refField.String()
+	isExternal := ref != "" && !strings.HasPrefix(ref, "#/components/"+kind.name+"/")
+	isInline := ref == ""
+	if !isExternal && !isInline {
+		return nil
+	}
+	name, err := f.intern(kind, ref, valueField)
+	if err != nil {
+		return err
+	}
+	refField.SetString(fmt.Sprintf("#/components/%s/%s", kind.name, name))
+	return nil
+}
+
+func (f *flattener) intern(kind componentRefKind, ref string, valueField reflect.Value) (string, error) {
+	raw, err := json.Marshal(valueField.Interface())
+	if err != nil {
+		return "", fmt.Errorf("Flatten: can't hash %s value: %v", kind.name, err)
+	}
+	sum := sha1.Sum(raw)
+	hash := hex.EncodeToString(sum[:])[:8]
+
+	byHash := f.byHash[kind.name]
+	if byHash == nil {
+		byHash = make(map[string]string)
+		f.byHash[kind.name] = byHash
+	}
+	if name, ok := byHash[hash]; ok {
+		return name, nil
+	}
+
+	name := fmt.Sprintf("%s_%s", basenameForRef(kind, ref), hash)
+	byHash[hash] = name
+
+	components := reflect.ValueOf(f.swagger).Elem().FieldByName("Components")
+	componentMap := components.FieldByName(kind.fieldName)
+	if componentMap.IsNil() {
+		componentMap.Set(reflect.MakeMap(componentMap.Type()))
+	}
+	newRef := reflect.New(kind.refType)
+	newRef.Elem().FieldByName("Value").Set(valueField)
+	componentMap.SetMapIndex(reflect.ValueOf(name), newRef)
+	f.roots[newRef.Interface()] = struct{}{}
+	return name, nil
+}
+
+// singularKindNames holds the kind names whose singular form isn't just
+// "strip a trailing s" - requestBodies' naive trim would otherwise mint
+// "requestBodie_<hash>".
+var singularKindNames = map[string]string{
+	"requestBodies": "requestBody",
+}
+
+// basenameForRef derives the human-readable part of a minted component name:
+// the referenced file's basename for an external ref, or the singular kind
+// name for an inline value with no ref at all.
+func basenameForRef(kind componentRefKind, ref string) string {
+	if ref == "" {
+		if singular, ok := singularKindNames[kind.name]; ok {
+			return singular
+		}
+		return strings.TrimSuffix(kind.name, "s")
+	}
+	uri := ref
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	base := path.Base(uri)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// Expand inlines every Ref.Value into its parent and clears Ref, so the
+// resulting document contains no refs at all (modulo cycles, see
+// ExpandOptions.AbsoluteCircularRef).
+func (swaggerLoader *SwaggerLoader) Expand(swagger *Swagger, opts ExpandOptions) error {
+	e := &expander{opts: opts}
+	// A separate visitation set from swaggerLoader.visited: that map is
+	// shared across every ref kind during resolution and reused wholesale
+	// by the time Expand runs, so it can't tell "currently being expanded"
+	// (a cycle) apart from "already resolved once".
+	inProgress := make(map[interface{}]struct{})
+	return e.walk(reflect.ValueOf(swagger), inProgress)
+}
+
+type expander struct {
+	opts ExpandOptions
+}
+
+func (e *expander) walk(v reflect.Value, inProgress map[interface{}]struct{}) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if _, cycle := inProgress[v.Interface()]; cycle {
+				if e.opts.AbsoluteCircularRef {
+					return errCircularRef
+				}
+				return fmt.Errorf("Expand: circular reference detected")
+			}
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if refField, valueField, ok := refWrapperFields(v); ok {
+			kind, isComponentRef := componentRefKindOf(v.Type())
+			if isComponentRef && e.opts.SkipSchemas && kind.name == "schemas" {
+				return nil
+			}
+			if ref := refField.String(); ref != "" {
+				if valueField.Kind() == reflect.Ptr && valueField.IsNil() {
+					// Nothing to inline: clearing Ref would lose the reference.
+					if e.opts.ContinueOnError {
+						return nil
+					}
+					return fmt.Errorf("Expand: unresolved reference '%s'", ref)
+				}
+				ptr := v.Addr().Interface()
+				inProgress[ptr] = struct{}{}
+				err := e.walk(valueField, inProgress)
+				delete(inProgress, ptr)
+				if err == errCircularRef {
+					// This edge closes the cycle: leave it exactly as it
+					// already was instead of inlining it, or the expanded
+					// document would end up with a literal pointer cycle
+					// through Value.
+					return nil
+				}
+				if err != nil {
+					if e.opts.ContinueOnError {
+						return nil
+					}
+					return err
+				}
+				refField.SetString("")
+				return nil
+			}
+			return e.walk(valueField, inProgress)
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanInterface() {
+				continue
+			}
+			if err := e.walk(v.Field(i), inProgress); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if err := e.walk(v.MapIndex(key), inProgress); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := e.walk(v.Index(i), inProgress); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package openapi3
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// schemaRefUsedInFirstResponse returns the *SchemaRef hanging off the first
+// response's first media type found anywhere in swagger.Paths.
+func schemaRefUsedInFirstResponse(t *testing.T, swagger *Swagger) *SchemaRef {
+	t.Helper()
+	for _, pathItem := range swagger.Paths {
+		for _, operation := range pathItem.Operations() {
+			for _, response := range operation.Responses {
+				for _, mediaType := range response.Value.Content {
+					return mediaType.Schema
+				}
+			}
+		}
+	}
+	t.Fatal("no response media type found in swagger.Paths")
+	return nil
+}
+
+// TestFlattenKeepsCanonicalComponentsSingular is a regression test for
+// Flatten minting a duplicate entry for every schema that was already
+// declared under Components.Schemas, since canonical definitions legitimately
+// have an empty Ref, same as a genuinely inline, unreferenced value.
+func TestFlattenKeepsCanonicalComponentsSingular(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": {"$ref": "#/components/schemas/Pet"}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object"
+				}
+			}
+		}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	require.NoError(t, loader.Flatten(swagger))
+
+	require.Len(t, swagger.Components.Schemas, 1, "Flatten must not mint a duplicate for an already-canonical component")
+	pet := swagger.Components.Schemas["Pet"]
+	require.NotNil(t, pet)
+	require.Empty(t, pet.Ref, "a component's own map entry is the definition, its Ref must stay empty")
+
+	used := schemaRefUsedInFirstResponse(t, swagger)
+	require.Equal(t, "#/components/schemas/Pet", used.Ref)
+}
+
+// TestFlattenHoistsInlineSchema exercises the actual hoisting path: an inline
+// schema with no Ref at all, reached from outside Components, must be minted
+// into a new Components.Schemas entry.
+func TestFlattenHoistsInlineSchema(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": {"type": "string"}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	require.NoError(t, loader.Flatten(swagger))
+
+	require.Len(t, swagger.Components.Schemas, 1)
+	used := schemaRefUsedInFirstResponse(t, swagger)
+	require.Contains(t, used.Ref, "#/components/schemas/")
+}
+
+// TestFlattenMintsRequestBodyName is a regression test for basenameForRef
+// singularizing "requestBodies" by naively trimming a trailing "s", which
+// produces "requestBodie" instead of "requestBody".
+func TestFlattenMintsRequestBodyName(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/pets": {
+				"post": {
+					"requestBody": {
+						"content": {
+							"application/json": {
+								"schema": {"type": "object"}
+							}
+						}
+					},
+					"responses": {
+						"200": {"description": "ok"}
+					}
+				}
+			}
+		},
+		"components": {}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	require.NoError(t, loader.Flatten(swagger))
+
+	require.Len(t, swagger.Components.RequestBodies, 1)
+	for name := range swagger.Components.RequestBodies {
+		require.True(t, strings.HasPrefix(name, "requestBody_"), "minted name %q must singularize to 'requestBody', not 'requestBodie'", name)
+	}
+}
+
+// TestExpandCircularReference checks that Expand's cycle handling honors
+// ExpandOptions.AbsoluteCircularRef instead of recursing forever or always
+// erroring.
+func TestExpandCircularReference(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Node": {
+					"type": "object",
+					"properties": {
+						"next": {"$ref": "#/components/schemas/Node"}
+					}
+				}
+			}
+		}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	err = loader.Expand(swagger, ExpandOptions{AbsoluteCircularRef: true})
+	require.NoError(t, err, "a cycle must not error when AbsoluteCircularRef is set")
+
+	next := swagger.Components.Schemas["Node"].Value.Properties["next"]
+	require.Equal(t, "#/components/schemas/Node", next.Ref, "the cycle-closing edge must be left as its existing ref, not inlined")
+	require.NotNil(t, next.Value, "the cycle-closing ref's Value is untouched too, still pointing at the live (cyclic) schema")
+
+	swagger2, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+	err = loader.Expand(swagger2, ExpandOptions{})
+	require.Error(t, err, "a cycle must error when AbsoluteCircularRef is not set")
+}
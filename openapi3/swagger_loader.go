@@ -5,7 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -25,44 +30,158 @@ func failedToResolveRefFragmentPart(value string, what string) error {
 	return fmt.Errorf("Failed to resolve '%s' in fragment in URI: '%s'", what, value)
 }
 
+// ReadFromURIFunc fetches the raw bytes backing location. Implementations are
+// free to support whatever URI schemes they like; DefaultReadFromURI handles
+// plain files plus, when IsExternalRefsAllowed is set, "http"/"https".
+type ReadFromURIFunc func(loader *SwaggerLoader, location *url.URL) ([]byte, error)
+
+// ResolutionCache lets a SwaggerLoader avoid re-reading, re-parsing and
+// re-resolving the same external document over and over while resolving a
+// spec that is split across many files all referencing a shared document
+// (e.g. common.yaml). Keys are the absolute URL of the referenced document
+// with its fragment stripped; values are either a parsed and resolved
+// *Swagger, or the document's raw []byte: transiently, before that parse has
+// happened, and for good in the case of the meta-schema NewSwaggerLoader
+// preloads, which is never parsed into a *Swagger.
+type ResolutionCache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+}
+
 type SwaggerLoader struct {
-	IsExternalRefsAllowed  bool
-	Context                context.Context
-	LoadSwaggerFromURIFunc func(loader *SwaggerLoader, url *url.URL) (*Swagger, error)
-	visited                map[interface{}]struct{}
+	IsExternalRefsAllowed bool
+	Context               context.Context
+	ReadFromURIFunc       ReadFromURIFunc
+	Cache                 ResolutionCache
+
+	// visited tracks already-resolved *SchemaRef/*ParameterRef/*RequestBodyRef/
+	// *ResponseRef/*HeaderRef/*SecuritySchemeRef nodes. Links, callbacks,
+	// examples and path items each get their own set below: since all of
+	// these are keyed by pointer identity, sharing one map across every kind
+	// risks one kind's node short-circuiting a lookup for another.
+	visited         map[interface{}]struct{}
+	visitedExample  map[interface{}]struct{}
+	visitedLink     map[interface{}]struct{}
+	visitedCallback map[interface{}]struct{}
+	visitedPathItem map[interface{}]struct{}
+	rootDir         string
 }
 
 func NewSwaggerLoader() *SwaggerLoader {
-	return &SwaggerLoader{}
+	return &SwaggerLoader{
+		Cache: newPreloadedResolutionCache(),
+	}
 }
 
-func (swaggerLoader *SwaggerLoader) LoadSwaggerFromURI(location *url.URL) (*Swagger, error) {
-	f := swaggerLoader.LoadSwaggerFromURIFunc
-	if f != nil {
-		return f(swaggerLoader, location)
+// DefaultReadFromURI is the default ReadFromURIFunc. It reads local files
+// directly; "http"/"https" URIs are only fetched when the loader has
+// IsExternalRefsAllowed set.
+func DefaultReadFromURI(swaggerLoader *SwaggerLoader, location *url.URL) ([]byte, error) {
+	if location.Scheme == "http" || location.Scheme == "https" {
+		if !swaggerLoader.IsExternalRefsAllowed {
+			return nil, fmt.Errorf("Encountered non-allowed external reference: '%s'", location.String())
+		}
+		resp, err := http.Get(location.String())
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode > 399 {
+			return nil, fmt.Errorf("Error loading '%s': request returned status code %d", location.String(), resp.StatusCode)
+		}
+		return ioutil.ReadAll(resp.Body)
 	}
 	if location.Scheme != "" || location.Host != "" || location.RawQuery != "" {
 		return nil, fmt.Errorf("Unsupported URI: '%s'", location.String())
 	}
-	data, err := ioutil.ReadFile(location.Path)
+	return ioutil.ReadFile(location.Path)
+}
+
+// absoluteURL rewrites a relative (scheme-less, rooted-less) location to be
+// relative to rootDir. rootDir starts out as the directory of the document
+// the loader was first pointed at, but resolveComponent retargets it, for
+// the duration of loading an external document, to that document's own
+// directory - so a relative ref found inside an external document resolves
+// against where that document lives, not where the root document does.
+// absoluteURL is a no-op once a document's refs have already been made
+// absolute, or before any root document has been loaded.
+func (swaggerLoader *SwaggerLoader) absoluteURL(location *url.URL) *url.URL {
+	if location.IsAbs() || strings.HasPrefix(location.Path, "/") || swaggerLoader.rootDir == "" {
+		return location
+	}
+	absolute := *location
+	absolute.Path = path.Join(swaggerLoader.rootDir, location.Path)
+	return &absolute
+}
+
+// readFromURI fetches location's bytes, consulting swaggerLoader.Cache first
+// and only falling through to ReadFromURIFunc on a cache miss.
+func (swaggerLoader *SwaggerLoader) readFromURI(location *url.URL) ([]byte, error) {
+	location = swaggerLoader.absoluteURL(location)
+	key := location.String()
+	cache := swaggerLoader.Cache
+	if cache != nil {
+		if cached, ok := cache.Get(key); ok {
+			data, ok := cached.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("Resolution cache entry for '%s' is not raw document bytes", key)
+			}
+			return data, nil
+		}
+	}
+	f := swaggerLoader.ReadFromURIFunc
+	if f == nil {
+		f = DefaultReadFromURI
+	}
+	data, err := f(swaggerLoader, location)
 	if err != nil {
 		return nil, err
 	}
-	return swaggerLoader.LoadSwaggerFromData(data)
+	if cache != nil {
+		cache.Set(key, data)
+	}
+	return data, nil
 }
 
-func (swaggerLoader *SwaggerLoader) LoadSwaggerFromFile(path string) (*Swagger, error) {
-	f := swaggerLoader.LoadSwaggerFromURIFunc
-	if f != nil {
-		return f(swaggerLoader, &url.URL{
-			Path: path,
-		})
+// LoadSwaggerFromURI consults swaggerLoader.Cache first for an already
+// parsed and resolved *Swagger for location, and only reads, parses and
+// resolves it itself on a cache miss - so a document referenced by dozens of
+// other files in the same spec pays that cost once, not once per reference.
+func (swaggerLoader *SwaggerLoader) LoadSwaggerFromURI(location *url.URL) (*Swagger, error) {
+	key := swaggerLoader.absoluteURL(location).String()
+	cache := swaggerLoader.Cache
+	if cache != nil {
+		if cached, ok := cache.Get(key); ok {
+			if swagger, ok := cached.(*Swagger); ok {
+				return swagger, nil
+			}
+		}
 	}
-	data, err := ioutil.ReadFile(path)
+	data, err := swaggerLoader.readFromURI(location)
 	if err != nil {
 		return nil, err
 	}
-	return swaggerLoader.LoadSwaggerFromData(data)
+	swagger, err := swaggerLoader.LoadSwaggerFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		cache.Set(key, swagger)
+	}
+	return swagger, nil
+}
+
+func (swaggerLoader *SwaggerLoader) LoadSwaggerFromFile(path string) (*Swagger, error) {
+	// path is relative to the working directory, not to rootDir, so it is
+	// made absolute before absoluteURL can join it onto rootDir.
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if swaggerLoader.rootDir == "" {
+		swaggerLoader.rootDir = filepath.Dir(absPath)
+	}
+	return swaggerLoader.LoadSwaggerFromURI(&url.URL{Path: absPath})
 }
 
 func (swaggerLoader *SwaggerLoader) LoadSwaggerFromData(data []byte) (*Swagger, error) {
@@ -76,6 +195,10 @@ func (swaggerLoader *SwaggerLoader) LoadSwaggerFromData(data []byte) (*Swagger,
 
 func (swaggerLoader *SwaggerLoader) ResolveRefsIn(swagger *Swagger) error {
 	swaggerLoader.visited = make(map[interface{}]struct{})
+	swaggerLoader.visitedExample = make(map[interface{}]struct{})
+	swaggerLoader.visitedLink = make(map[interface{}]struct{})
+	swaggerLoader.visitedCallback = make(map[interface{}]struct{})
+	swaggerLoader.visitedPathItem = make(map[interface{}]struct{})
 
 	// Visit all components
 	if m := swagger.Components.Headers; m != nil {
@@ -126,67 +249,262 @@ func (swaggerLoader *SwaggerLoader) ResolveRefsIn(swagger *Swagger) error {
 			}
 		}
 	}
+	if m := swagger.Components.Examples; m != nil {
+		for _, component := range m {
+			err := swaggerLoader.resolveExampleRef(swagger, component)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if m := swagger.Components.Links; m != nil {
+		for _, component := range m {
+			err := swaggerLoader.resolveLinkRef(swagger, component)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if m := swagger.Components.Callbacks; m != nil {
+		for _, component := range m {
+			err := swaggerLoader.resolveCallbackRef(swagger, component)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-	// Visit all operations
+	// Visit all paths, dereferencing pathItem.$ref before looking at its
+	// operations.
 	if paths := swagger.Paths; paths != nil {
 		for _, pathItem := range paths {
-			if pathItem == nil {
-				continue
+			if err := swaggerLoader.resolvePathItemRef(swagger, pathItem); err != nil {
+				return err
 			}
-			for _, operation := range pathItem.Operations() {
-				if parameters := operation.Parameters; parameters != nil {
-					for _, parameter := range parameters {
-						err := swaggerLoader.resolveParameterRef(swagger, parameter)
-						if err != nil {
-							return err
-						}
-					}
-				}
-				if requestBody := operation.RequestBody; requestBody != nil {
-					err := swaggerLoader.resolveRequestBodyRef(swagger, requestBody)
-					if err != nil {
+		}
+	}
+	return nil
+}
+
+// resolveOperation resolves every ref hanging off operation: its parameters,
+// request body, responses (and their links), and callbacks.
+func (swaggerLoader *SwaggerLoader) resolveOperation(swagger *Swagger, operation *Operation) error {
+	if operation == nil {
+		return nil
+	}
+	if parameters := operation.Parameters; parameters != nil {
+		for _, parameter := range parameters {
+			if err := swaggerLoader.resolveParameterRef(swagger, parameter); err != nil {
+				return err
+			}
+		}
+	}
+	if requestBody := operation.RequestBody; requestBody != nil {
+		if err := swaggerLoader.resolveRequestBodyRef(swagger, requestBody); err != nil {
+			return err
+		}
+	}
+	if responses := operation.Responses; responses != nil {
+		for _, response := range responses {
+			if err := swaggerLoader.resolveResponseRef(swagger, response); err != nil {
+				return err
+			}
+			if response.Value != nil {
+				for _, link := range response.Value.Links {
+					if err := swaggerLoader.resolveLinkRef(swagger, link); err != nil {
 						return err
 					}
 				}
-				if responses := operation.Responses; responses != nil {
-					for _, response := range responses {
-						err := swaggerLoader.resolveResponseRef(swagger, response)
-						if err != nil {
-							return err
-						}
-					}
-				}
+			}
+		}
+	}
+	if callbacks := operation.Callbacks; callbacks != nil {
+		for _, callback := range callbacks {
+			if err := swaggerLoader.resolveCallbackRef(swagger, callback); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
-func (swaggerLoader *SwaggerLoader) resolveComponent(swagger *Swagger, ref string, prefix string) (components *Components, id string, err error) {
+// resolveComponent resolves ref against swagger using RFC 6901 JSON Pointer
+// evaluation: ref's fragment is split into '/'-separated tokens (each
+// unescaped with '~1' -> '/' and '~0' -> '~'), and the tokens are walked one
+// by one through the parsed document, through map keys, slice/array indices
+// and struct fields named by their `json` tag. The value found at the end of
+// the walk is returned so callers can type-assert it into the concrete *Ref
+// they were expecting.
+//
+// This means a ref no longer has to point into a components map: it may
+// point at any node in the document, e.g.
+// "#/paths/~1pets~1{id}/get/responses/200/content/application~1json/schema/properties/name".
+func (swaggerLoader *SwaggerLoader) resolveComponent(swagger *Swagger, ref string) (interface{}, error) {
+	var root interface{} = swagger
 	if !strings.HasPrefix(ref, "#") {
 		if !swaggerLoader.IsExternalRefsAllowed {
-			return nil, "", fmt.Errorf("Encountered non-allowed external reference: '%s'", ref)
+			return nil, fmt.Errorf("Encountered non-allowed external reference: '%s'", ref)
 		}
 		parsedURL, err := url.Parse(ref)
 		if err != nil {
-			return nil, "", fmt.Errorf("Can't parse reference: '%s': %v", ref, parsedURL)
+			return nil, fmt.Errorf("Can't parse reference: '%s': %v", ref, err)
 		}
 		fragment := parsedURL.Fragment
 		parsedURL.Fragment = ""
-		swagger, err = swaggerLoader.LoadSwaggerFromURI(parsedURL)
+		location := swaggerLoader.absoluteURL(parsedURL)
+
+		// While loading location, relative refs found inside it must resolve
+		// against its own directory, not the directory of whichever document
+		// sent us here - otherwise a spec split across nested subdirectories
+		// resolves a second-level relative ref against the wrong base path.
+		previousRootDir := swaggerLoader.rootDir
+		swaggerLoader.rootDir = path.Dir(location.Path)
+		external, err := swaggerLoader.loadExternalDocument(location)
+		swaggerLoader.rootDir = previousRootDir
 		if err != nil {
-			return nil, "", fmt.Errorf("Error while resolving reference '%s': %v", ref, err)
+			return nil, fmt.Errorf("Error while resolving reference '%s': %v", ref, err)
+		}
+		root = external
+		ref = "#" + fragment
+	}
+	fragment := strings.TrimPrefix(ref, "#")
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return nil, failedToResolveRefFragment(ref)
+	}
+	resolved, err := drillIntoSwagger(root, jsonPointerTokens(fragment))
+	if err != nil {
+		return nil, failedToResolveRefFragmentPart(ref, err.Error())
+	}
+	return resolved, nil
+}
+
+// loadExternalDocument loads the document at location as whatever shape it
+// actually has. Every external ref is assumed to point at an OpenAPI
+// document and is loaded (and cached, once parsed and resolved) as a
+// *Swagger via LoadSwaggerFromURI - except the OpenAPI 3.0 meta-schema
+// itself, which is a plain JSON Schema document sharing none of Swagger's
+// field names, so it's decoded generically instead: unmarshaling it into a
+// *Swagger would silently come back empty, and any $ref into it (e.g. into
+// "#/definitions/Schema") would then fail to resolve.
+func (swaggerLoader *SwaggerLoader) loadExternalDocument(location *url.URL) (interface{}, error) {
+	if location.String() == openapi30MetaSchemaURL {
+		data, err := swaggerLoader.readFromURI(location)
+		if err != nil {
+			return nil, err
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	return swaggerLoader.LoadSwaggerFromURI(location)
+}
+
+// jsonPointerTokens splits a JSON Pointer (e.g. "/components/schemas/Pet")
+// into its unescaped reference tokens, per RFC 6901.
+func jsonPointerTokens(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	parts := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	tokens := make([]string, len(parts))
+	for i, part := range parts {
+		tokens[i] = unescapeJSONPointerToken(part)
+	}
+	return tokens
+}
+
+func unescapeJSONPointerToken(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	token = strings.Replace(token, "~0", "~", -1)
+	return token
+}
+
+// drillIntoSwagger walks tokens one by one through root, descending into
+// map values, slice/array elements and struct fields (matched by their
+// `json` tag). The value returned is exactly what's stored at that final
+// position (e.g. the *SchemaRef held in a parent schema's Properties map),
+// not dereferenced any further, so callers can type-assert it into the
+// concrete *Ref they expected and inspect its own Ref/Value themselves.
+func drillIntoSwagger(root interface{}, tokens []string) (interface{}, error) {
+	cursor := reflect.ValueOf(root)
+	for _, token := range tokens {
+		// To navigate further we need to see past pointers and, if an
+		// earlier hop landed on a $ref'd node, past the wrapper into the
+		// Value it points at - but only while there's still a next token to
+		// resolve; the final landed value is returned exactly as stored.
+		nav := intoRefValue(derefPointer(cursor))
+		if !nav.IsValid() {
+			return nil, failedToResolveRefFragmentPart(token, "<nil>")
+		}
+		switch nav.Kind() {
+		case reflect.Map:
+			value := nav.MapIndex(reflect.ValueOf(token))
+			if !value.IsValid() {
+				return nil, failedToResolveRefFragmentPart(token, "map key")
+			}
+			cursor = value
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= nav.Len() {
+				return nil, failedToResolveRefFragmentPart(token, "array index")
+			}
+			cursor = nav.Index(index)
+		case reflect.Struct:
+			field, ok := structFieldByJSONTag(nav, token)
+			if !ok {
+				return nil, failedToResolveRefFragmentPart(token, "field")
+			}
+			cursor = field
+		default:
+			return nil, failedToResolveRefFragmentPart(token, "scalar")
 		}
-		ref = fragment
 	}
-	if !strings.HasPrefix(ref, prefix) {
-		return nil, "", failedToResolveRefFragment(ref)
+	if !cursor.IsValid() || !cursor.CanInterface() {
+		return nil, nil
 	}
-	id = ref[len(prefix):]
-	if strings.IndexByte(id, '/') >= 0 {
-		return nil, "", failedToResolveRefFragmentPart(ref, id)
+	return cursor.Interface(), nil
+}
+
+// derefPointer dereferences pointers and interfaces down to the concrete
+// value underneath.
+func derefPointer(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
 	}
-	return &swagger.Components, id, nil
+	return v
+}
+
+// intoRefValue transparently drills through a wrapper `*Ref` type (anything
+// with a string `Ref` field alongside a `Value` field) into the node it
+// points at, so a path that passes through a $ref'd node on its way to a
+// deeper one keeps resolving against live data.
+func intoRefValue(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return v
+	}
+	refField := v.FieldByName("Ref")
+	valueField := v.FieldByName("Value")
+	if refField.IsValid() && refField.Kind() == reflect.String && valueField.IsValid() {
+		return derefPointer(valueField)
+	}
+	return v
+}
+
+func structFieldByJSONTag(v reflect.Value, token string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == token {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
 }
 
 func (swaggerLoader *SwaggerLoader) resolveHeaderRef(swagger *Swagger, component *HeaderRef) error {
@@ -198,25 +516,19 @@ func (swaggerLoader *SwaggerLoader) resolveHeaderRef(swagger *Swagger, component
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/headers/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.Headers
-		if definitions == nil {
-			return failedToResolveRefFragment(ref)
+		definition, ok := resolved.(*HeaderRef)
+		if !ok {
+			return failedToResolveRefFragmentPart(ref, "headers")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragment(ref)
-		}
-		err = swaggerLoader.resolveHeaderRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveHeaderRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	value := component.Value
 	if value == nil {
@@ -240,25 +552,19 @@ func (swaggerLoader *SwaggerLoader) resolveParameterRef(swagger *Swagger, compon
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/parameters/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.Parameters
-		if definitions == nil {
+		definition, ok := resolved.(*ParameterRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "parameters")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
-		}
-		err = swaggerLoader.resolveParameterRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveParameterRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	value := component.Value
 	if value == nil {
@@ -270,6 +576,13 @@ func (swaggerLoader *SwaggerLoader) resolveParameterRef(swagger *Swagger, compon
 			return err
 		}
 	}
+	if examples := value.Examples; examples != nil {
+		for _, example := range examples {
+			if err := swaggerLoader.resolveExampleRef(swagger, example); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -282,25 +595,19 @@ func (swaggerLoader *SwaggerLoader) resolveRequestBodyRef(swagger *Swagger, comp
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/requestBodies/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.RequestBodies
-		if definitions == nil {
+		definition, ok := resolved.(*RequestBodyRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "requestBodies")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
-		}
-		err = swaggerLoader.resolveRequestBodyRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveRequestBodyRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	value := component.Value
 	if value == nil {
@@ -314,6 +621,11 @@ func (swaggerLoader *SwaggerLoader) resolveRequestBodyRef(swagger *Swagger, comp
 					return err
 				}
 			}
+			for _, example := range contentType.Examples {
+				if err := swaggerLoader.resolveExampleRef(swagger, example); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	return nil
@@ -328,25 +640,19 @@ func (swaggerLoader *SwaggerLoader) resolveResponseRef(swagger *Swagger, compone
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/responses/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.Responses
-		if definitions == nil {
+		definition, ok := resolved.(*ResponseRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "responses")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
-		}
-		err = swaggerLoader.resolveResponseRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveResponseRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	value := component.Value
 	if value == nil {
@@ -364,6 +670,11 @@ func (swaggerLoader *SwaggerLoader) resolveResponseRef(swagger *Swagger, compone
 				}
 				contentType.Schema = schema
 			}
+			for _, example := range contentType.Examples {
+				if err := swaggerLoader.resolveExampleRef(swagger, example); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	return nil
@@ -378,25 +689,19 @@ func (swaggerLoader *SwaggerLoader) resolveSchemaRef(swagger *Swagger, component
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/schemas/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.Schemas
-		if definitions == nil {
+		definition, ok := resolved.(*SchemaRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "schemas")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
-		}
-		err = swaggerLoader.resolveSchemaRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveSchemaRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	value := component.Value
 
@@ -433,49 +738,148 @@ func (swaggerLoader *SwaggerLoader) resolveSecuritySchemeRef(swagger *Swagger, c
 	visited[component] = struct{}{}
 
 	// Resolve ref
-	const prefix = "#/components/securitySchemes/"
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.SecuritySchemes
-		if definitions == nil {
+		definition, ok := resolved.(*SecuritySchemeRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "securitySchemes")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
-		}
-		err = swaggerLoader.resolveSecuritySchemeRef(swagger, resolved)
-		if err != nil {
+		if err := swaggerLoader.resolveSecuritySchemeRef(swagger, definition); err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		component.Value = definition.Value
 	}
 	return nil
 }
 
 func (swaggerLoader *SwaggerLoader) resolveExampleRef(swagger *Swagger, component *ExampleRef) error {
-	const prefix = "#/components/examples"
+	// Prevent infinite recursion
+	visited := swaggerLoader.visitedExample
+	if _, isVisited := visited[component]; isVisited {
+		return nil
+	}
+	visited[component] = struct{}{}
+
+	// Resolve ref
 	if ref := component.Ref; len(ref) > 0 {
-		components, id, err := swaggerLoader.resolveComponent(swagger, ref, prefix)
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		definitions := components.Examples
-		if definitions == nil {
+		definition, ok := resolved.(*ExampleRef)
+		if !ok {
 			return failedToResolveRefFragmentPart(ref, "examples")
 		}
-		resolved := definitions[id]
-		if resolved == nil {
-			return failedToResolveRefFragmentPart(ref, id)
+		if err := swaggerLoader.resolveExampleRef(swagger, definition); err != nil {
+			return err
+		}
+		component.Value = definition.Value
+	}
+	return nil
+}
+
+func (swaggerLoader *SwaggerLoader) resolveLinkRef(swagger *Swagger, component *LinkRef) error {
+	// Prevent infinite recursion
+	visited := swaggerLoader.visitedLink
+	if _, isVisited := visited[component]; isVisited {
+		return nil
+	}
+	visited[component] = struct{}{}
+
+	// Resolve ref
+	if ref := component.Ref; len(ref) > 0 {
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
+		if err != nil {
+			return err
+		}
+		definition, ok := resolved.(*LinkRef)
+		if !ok {
+			return failedToResolveRefFragmentPart(ref, "links")
+		}
+		if err := swaggerLoader.resolveLinkRef(swagger, definition); err != nil {
+			return err
 		}
-		err = swaggerLoader.resolveExampleRef(swagger, resolved)
+		component.Value = definition.Value
+	}
+	return nil
+}
+
+func (swaggerLoader *SwaggerLoader) resolveCallbackRef(swagger *Swagger, component *CallbackRef) error {
+	// Prevent infinite recursion
+	visited := swaggerLoader.visitedCallback
+	if _, isVisited := visited[component]; isVisited {
+		return nil
+	}
+	visited[component] = struct{}{}
+
+	// Resolve ref
+	if ref := component.Ref; len(ref) > 0 {
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
 		if err != nil {
 			return err
 		}
-		component.Value = resolved.Value
+		definition, ok := resolved.(*CallbackRef)
+		if !ok {
+			return failedToResolveRefFragmentPart(ref, "callbacks")
+		}
+		if err := swaggerLoader.resolveCallbackRef(swagger, definition); err != nil {
+			return err
+		}
+		component.Value = definition.Value
+	}
+	value := component.Value
+	if value == nil {
+		return nil
+	}
+	for _, pathItem := range *value {
+		if err := swaggerLoader.resolvePathItemRef(swagger, pathItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePathItemRef resolves pathItem's own $ref, if any, copying the
+// referenced PathItem's contents into pathItem before looking at its
+// operations - a PathItem that only has its Ref field populated has no
+// operations of its own, so resolving its operations without first
+// dereferencing it would silently skip every path underneath it.
+func (swaggerLoader *SwaggerLoader) resolvePathItemRef(swagger *Swagger, pathItem *PathItem) error {
+	if pathItem == nil {
+		return nil
+	}
+
+	// Prevent infinite recursion
+	visited := swaggerLoader.visitedPathItem
+	if _, isVisited := visited[pathItem]; isVisited {
+		return nil
+	}
+	visited[pathItem] = struct{}{}
+
+	// Resolve ref
+	if ref := pathItem.Ref; len(ref) > 0 {
+		resolved, err := swaggerLoader.resolveComponent(swagger, ref)
+		if err != nil {
+			return err
+		}
+		definition, ok := resolved.(*PathItem)
+		if !ok {
+			return failedToResolveRefFragmentPart(ref, "PathItem")
+		}
+		if err := swaggerLoader.resolvePathItemRef(swagger, definition); err != nil {
+			return err
+		}
+		*pathItem = *definition
+		pathItem.Ref = ref
+	}
+
+	for _, operation := range pathItem.Operations() {
+		if err := swaggerLoader.resolveOperation(swagger, operation); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,185 @@
+package openapi3
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeJSONPointerToken(t *testing.T) {
+	require.Equal(t, "a/b", unescapeJSONPointerToken("a~1b"))
+	require.Equal(t, "a~b", unescapeJSONPointerToken("a~0b"))
+	// "~01" must unescape to "~1" (a literal tilde followed by "1"), not "/" -
+	// the '~1' -> '/' replacement has to run before '~0' -> '~' does, or an
+	// escaped tilde could be misread as the start of another escape.
+	require.Equal(t, "~1", unescapeJSONPointerToken("~01"))
+}
+
+func TestJSONPointerTokens(t *testing.T) {
+	require.Nil(t, jsonPointerTokens(""))
+	require.Equal(t, []string{"components", "schemas", "Pet"}, jsonPointerTokens("/components/schemas/Pet"))
+	require.Equal(t, []string{"paths", "/pets/{id}", "get"}, jsonPointerTokens("/paths/~1pets~1{id}/get"))
+}
+
+// TestResolveComponentArbitraryPath exercises resolveComponent/drillIntoSwagger
+// against a ref that does not point into a components map at all, since
+// ResolveRefsIn now evaluates a ref as a full RFC 6901 JSON Pointer over the
+// whole document rather than only ever looking inside Components.
+func TestResolveComponentArbitraryPath(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"properties": {
+											"name": {"type": "string"}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"PetName": {
+					"$ref": "#/paths/~1pets/get/responses/200/content/application~1json/schema/properties/name"
+				}
+			}
+		}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	petName := swagger.Components.Schemas["PetName"]
+	require.NotNil(t, petName)
+	require.NotNil(t, petName.Value)
+	require.Equal(t, "string", petName.Value.Type)
+}
+
+// TestLoadSwaggerFromFileResolvesNestedDirectoryExternalRef is a regression
+// test for rootDir being a single loader-wide field set once from the root
+// document's directory: a relative external ref found inside a document
+// that itself lives in a subdirectory must resolve against that document's
+// own directory, not the root document's, or a spec split across nested
+// subdirectories fails to load its second-level refs.
+func TestLoadSwaggerFromFileResolvesNestedDirectoryExternalRef(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kin-openapi-nested")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	subDir := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(subDir, 0o755))
+
+	const info = `"info": {"title": "t", "version": "1"}`
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root.json"), []byte(`{
+		"openapi": "3.0.0",
+		`+info+`,
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Foo": {"$ref": "sub/common.json#/components/schemas/Common"}
+			}
+		}
+	}`), 0o644))
+
+	// common.json's own external ref is relative to sub/, not to dir/ - it
+	// must resolve to sub/inner.json, not the (nonexistent) dir/inner.json.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(subDir, "common.json"), []byte(`{
+		"openapi": "3.0.0",
+		`+info+`,
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Common": {"$ref": "inner.json#/components/schemas/Inner"}
+			}
+		}
+	}`), 0o644))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(subDir, "inner.json"), []byte(`{
+		"openapi": "3.0.0",
+		`+info+`,
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Inner": {"type": "string"}
+			}
+		}
+	}`), 0o644))
+
+	loader := NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+	swagger, err := loader.LoadSwaggerFromFile(filepath.Join(dir, "root.json"))
+	require.NoError(t, err)
+
+	foo := swagger.Components.Schemas["Foo"]
+	require.NotNil(t, foo)
+	require.NotNil(t, foo.Value)
+	require.Equal(t, "string", foo.Value.Type)
+}
+
+// TestResolveComponentMetaSchemaIsDrillable is a regression test for the
+// preloaded OpenAPI 3.0 meta-schema never actually being reachable through a
+// $ref: it's a plain JSON-Schema document, not a Swagger document, so it
+// must be served as a raw JSON-Schema node rather than unmarshaled into a
+// (structurally incompatible, so effectively empty) *Swagger.
+func TestResolveComponentMetaSchemaIsDrillable(t *testing.T) {
+	loader := NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+
+	resolved, err := loader.resolveComponent(&Swagger{}, openapi30MetaSchemaURL+"#/definitions/Schema")
+	require.NoError(t, err)
+	schema, ok := resolved.(map[string]interface{})
+	require.True(t, ok, "the meta-schema's own definitions are raw JSON-Schema nodes, not Swagger types")
+	require.Equal(t, "object", schema["type"])
+}
+
+// TestResolveComponentReturnsConcreteRefType guards against drillIntoSwagger
+// unwrapping the final landed node one step too far: resolving a ref that
+// lands on a property (itself a *SchemaRef held in a Properties map) must
+// hand back that *SchemaRef, not the bare *Schema underneath it, since every
+// resolve*Ref caller type-asserts the result into the ref wrapper it expects.
+func TestResolveComponentReturnsConcreteRefType(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"}
+					}
+				},
+				"PetName": {
+					"$ref": "#/components/schemas/Pet/properties/name"
+				}
+			}
+		}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	petName := swagger.Components.Schemas["PetName"]
+	require.NotNil(t, petName.Value)
+	require.Equal(t, "string", petName.Value.Type)
+}
@@ -0,0 +1,100 @@
+package openapi3
+
+import "reflect"
+
+// PruneUnusedComponents removes every entry in swagger.Components that
+// isn't reachable from swagger.Paths, swagger.Security or swagger.Servers.
+// It works in two passes - collect every ref (and, for security schemes,
+// every scheme name) reachable from those roots, then delete any component
+// map entry whose ref isn't in that set - repeating until a pass removes
+// nothing. Reachability is computed from those roots alone, never from other
+// component entries, so a schema referenced only by an unreachable schema
+// (e.g. as one of its properties) is unreachable too and goes in the same pass.
+func PruneUnusedComponents(swagger *Swagger) (removedCount int, err error) {
+	for {
+		reachable, err := reachableComponentRefs(swagger)
+		if err != nil {
+			return removedCount, err
+		}
+		removed := pruneUnreachableComponents(&swagger.Components, reachable)
+		if removed == 0 {
+			return removedCount, nil
+		}
+		removedCount += removed
+	}
+}
+
+func reachableComponentRefs(swagger *Swagger) (map[string]bool, error) {
+	reachable := make(map[string]bool)
+	mark := func(ref string) {
+		if ref != "" {
+			reachable[ref] = true
+		}
+	}
+
+	w := &refWalker{visited: make(map[interface{}]struct{})}
+	w.visit = func(ref RefWrapper) (bool, error) {
+		mark(ref.Ref)
+		return true, nil
+	}
+	if err := w.walkPaths(swagger.Paths); err != nil {
+		return nil, err
+	}
+
+	markSecurityRequirements(swagger.Security, mark)
+	for _, pathItem := range swagger.Paths {
+		if pathItem == nil {
+			continue
+		}
+		for _, operation := range pathItem.Operations() {
+			if operation.Security != nil {
+				markSecurityRequirements(*operation.Security, mark)
+			}
+		}
+	}
+	return reachable, nil
+}
+
+// markSecurityRequirements marks every security scheme named by reqs as
+// reachable. Security requirements reference scheme names directly rather
+// than through a $ref, so they're translated into the equivalent
+// "#/components/securitySchemes/<name>" ref for tracking purposes.
+func markSecurityRequirements(reqs SecurityRequirements, mark func(string)) {
+	for _, req := range reqs {
+		for name := range req {
+			mark("#/components/securitySchemes/" + name)
+		}
+	}
+}
+
+// pruneUnreachableComponents deletes every map entry in components whose
+// "#/components/<kind>/<key>" ref isn't in reachable, for each ref kind
+// Flatten/Expand already know about (see componentRefKinds), plus links,
+// callbacks and security schemes; security schemes aren't reached via a $ref
+// but via a security requirement's scheme name.
+func pruneUnreachableComponents(components *Components, reachable map[string]bool) int {
+	removed := 0
+	componentsValue := reflect.ValueOf(components).Elem()
+
+	prune := func(kind, fieldName string) {
+		m := componentsValue.FieldByName(fieldName)
+		if !m.IsValid() || m.IsNil() {
+			return
+		}
+		for _, key := range m.MapKeys() {
+			if !reachable["#/components/"+kind+"/"+key.String()] {
+				m.SetMapIndex(key, reflect.Value{})
+				removed++
+			}
+		}
+	}
+
+	for _, kind := range componentRefKinds() {
+		prune(kind.name, kind.fieldName)
+	}
+	prune("links", "Links")
+	prune("callbacks", "Callbacks")
+	prune("securitySchemes", "SecuritySchemes")
+
+	return removed
+}
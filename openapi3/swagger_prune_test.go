@@ -0,0 +1,98 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPruneUnusedComponentsKeepsAllOfReferencedSchema is a regression test
+// for PruneUnusedComponents deleting a schema that's only reachable through
+// composition (allOf/oneOf/anyOf/not) rather than Items/Properties/
+// AdditionalProperties, since reachability is computed from the same walk
+// Walk uses.
+func TestPruneUnusedComponentsKeepsAllOfReferencedSchema(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": {"$ref": "#/components/schemas/Dog"}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Base": {"type": "object"},
+				"Dog": {
+					"allOf": [
+						{"$ref": "#/components/schemas/Base"},
+						{"type": "object"}
+					]
+				},
+				"Unused": {"type": "object"}
+			}
+		}
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	removed, err := PruneUnusedComponents(swagger)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	require.Contains(t, swagger.Components.Schemas, "Base", "Base is only reachable through Dog's allOf and must survive pruning")
+	require.Contains(t, swagger.Components.Schemas, "Dog")
+	require.NotContains(t, swagger.Components.Schemas, "Unused")
+}
+
+// TestPruneUnusedComponentsFixpoint checks that a chain of unused schemas
+// (one referenced only as a property of the other) is fully cleaned up, not
+// just the first link, while a security scheme named by a top-level security
+// requirement survives.
+func TestPruneUnusedComponentsFixpoint(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {},
+		"components": {
+			"securitySchemes": {
+				"ApiKey": {"type": "apiKey", "name": "X-Api-Key", "in": "header"}
+			},
+			"schemas": {
+				"Unused": {
+					"type": "object",
+					"properties": {
+						"child": {"$ref": "#/components/schemas/AlsoUnused"}
+					}
+				},
+				"AlsoUnused": {"type": "object"}
+			}
+		},
+		"security": [{"ApiKey": []}]
+	}`)
+
+	loader := NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+
+	removed, err := PruneUnusedComponents(swagger)
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	require.NotContains(t, swagger.Components.Schemas, "Unused")
+	require.NotContains(t, swagger.Components.Schemas, "AlsoUnused")
+	require.Contains(t, swagger.Components.SecuritySchemes, "ApiKey", "a security scheme named by a top-level security requirement must survive")
+}
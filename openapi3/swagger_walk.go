@@ -0,0 +1,299 @@
+package openapi3
+
+// RefWrapper gives Walk's visit callback a uniform view of one of the
+// ref-bearing node types an OpenAPI 3 document can contain, so callers like
+// PruneUnusedComponents don't need their own type switch over every ref
+// kind. Exactly one of the typed fields is set, matching the concrete node
+// being visited; HasValue reports whether that node's Value has already
+// been populated (by ResolveRefsIn) at the time Walk ran.
+type RefWrapper struct {
+	Ref      string
+	HasValue bool
+
+	Schema         *SchemaRef
+	Parameter      *ParameterRef
+	Response       *ResponseRef
+	RequestBody    *RequestBodyRef
+	Header         *HeaderRef
+	Example        *ExampleRef
+	Link           *LinkRef
+	Callback       *CallbackRef
+	SecurityScheme *SecuritySchemeRef
+	PathItem       *PathItem
+}
+
+// Walk visits ref-bearing nodes reachable from swagger: every entry under
+// Components, every path, and every operation hanging off those paths
+// (including operation callbacks and the path items nested inside them).
+// Below an operation it visits parameters and their schemas, the request
+// body and response content schemas, and response links. It does not visit
+// path-level parameters, response or encoding headers, parameter content,
+// or the examples under parameters, headers and media types.
+// visit is called once per node, in parent-before-child order; returning
+// descend=false skips that node's children without stopping the walk, and a
+// non-nil error aborts it. Walk guards against cycles with its own
+// visitation set, independent of SwaggerLoader.ResolveRefsIn's.
+func Walk(swagger *Swagger, visit func(RefWrapper) (descend bool, err error)) error {
+	w := &refWalker{visit: visit, visited: make(map[interface{}]struct{})}
+	if err := w.walkComponents(&swagger.Components); err != nil {
+		return err
+	}
+	return w.walkPaths(swagger.Paths)
+}
+
+type refWalker struct {
+	visit   func(RefWrapper) (bool, error)
+	visited map[interface{}]struct{}
+}
+
+// enter reports whether ptr has not yet been visited on this walk,
+// recording it as visited if so.
+func (w *refWalker) enter(ptr interface{}) bool {
+	if _, ok := w.visited[ptr]; ok {
+		return false
+	}
+	w.visited[ptr] = struct{}{}
+	return true
+}
+
+func (w *refWalker) walkComponents(components *Components) error {
+	for _, ref := range components.Headers {
+		if err := w.walkHeaderRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Parameters {
+		if err := w.walkParameterRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.RequestBodies {
+		if err := w.walkRequestBodyRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Responses {
+		if err := w.walkResponseRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Schemas {
+		if err := w.walkSchemaRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.SecuritySchemes {
+		if err := w.walkSecuritySchemeRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Examples {
+		if err := w.walkExampleRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Links {
+		if err := w.walkLinkRef(ref); err != nil {
+			return err
+		}
+	}
+	for _, ref := range components.Callbacks {
+		if err := w.walkCallbackRef(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkPaths(paths Paths) error {
+	for _, pathItem := range paths {
+		if err := w.walkPathItem(pathItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkPathItem(item *PathItem) error {
+	if item == nil || !w.enter(item) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: item.Ref, HasValue: true, PathItem: item})
+	if err != nil || !descend {
+		return err
+	}
+	for _, operation := range item.Operations() {
+		if err := w.walkOperation(operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkOperation(operation *Operation) error {
+	if operation == nil {
+		return nil
+	}
+	for _, parameter := range operation.Parameters {
+		if err := w.walkParameterRef(parameter); err != nil {
+			return err
+		}
+	}
+	if err := w.walkRequestBodyRef(operation.RequestBody); err != nil {
+		return err
+	}
+	for _, response := range operation.Responses {
+		if err := w.walkResponseRef(response); err != nil {
+			return err
+		}
+	}
+	for _, callback := range operation.Callbacks {
+		if err := w.walkCallbackRef(callback); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkSchemaRef(ref *SchemaRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Schema: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	value := ref.Value
+	if err := w.walkSchemaRef(value.Items); err != nil {
+		return err
+	}
+	for _, property := range value.Properties {
+		if err := w.walkSchemaRef(property); err != nil {
+			return err
+		}
+	}
+	if err := w.walkSchemaRef(value.AdditionalProperties); err != nil {
+		return err
+	}
+	for _, schema := range value.AllOf {
+		if err := w.walkSchemaRef(schema); err != nil {
+			return err
+		}
+	}
+	for _, schema := range value.OneOf {
+		if err := w.walkSchemaRef(schema); err != nil {
+			return err
+		}
+	}
+	for _, schema := range value.AnyOf {
+		if err := w.walkSchemaRef(schema); err != nil {
+			return err
+		}
+	}
+	return w.walkSchemaRef(value.Not)
+}
+
+func (w *refWalker) walkParameterRef(ref *ParameterRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Parameter: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	return w.walkSchemaRef(ref.Value.Schema)
+}
+
+func (w *refWalker) walkRequestBodyRef(ref *RequestBodyRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, RequestBody: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	for _, mediaType := range ref.Value.Content {
+		if mediaType == nil {
+			continue
+		}
+		if err := w.walkSchemaRef(mediaType.Schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkResponseRef(ref *ResponseRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Response: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	for _, mediaType := range ref.Value.Content {
+		if mediaType == nil {
+			continue
+		}
+		if err := w.walkSchemaRef(mediaType.Schema); err != nil {
+			return err
+		}
+	}
+	for _, link := range ref.Value.Links {
+		if err := w.walkLinkRef(link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *refWalker) walkHeaderRef(ref *HeaderRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Header: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	return w.walkSchemaRef(ref.Value.Schema)
+}
+
+func (w *refWalker) walkSecuritySchemeRef(ref *SecuritySchemeRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	_, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, SecurityScheme: ref})
+	return err
+}
+
+func (w *refWalker) walkExampleRef(ref *ExampleRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	_, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Example: ref})
+	return err
+}
+
+func (w *refWalker) walkLinkRef(ref *LinkRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	_, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Link: ref})
+	return err
+}
+
+func (w *refWalker) walkCallbackRef(ref *CallbackRef) error {
+	if ref == nil || !w.enter(ref) {
+		return nil
+	}
+	descend, err := w.visit(RefWrapper{Ref: ref.Ref, HasValue: ref.Value != nil, Callback: ref})
+	if err != nil || !descend || ref.Value == nil {
+		return err
+	}
+	for _, pathItem := range *ref.Value {
+		if err := w.walkPathItem(pathItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}